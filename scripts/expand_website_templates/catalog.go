@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/golangci/golangci-lint/internal/renameio"
+	"github.com/golangci/golangci-lint/pkg/lint/linter"
+)
+
+const (
+	lintersCatalogPath = "docs/assets/linters.json"
+	configSchemaPath   = "docs/assets/golangci-lint.schema.json"
+)
+
+// LinterCatalogEntry is the machine-readable counterpart of a row in the
+// markdown linters table. It's serialized to lintersCatalogPath so tools
+// other than the docs site (editor plugins, release scripts) don't have to
+// scrape markdown to know what linters a release ships with.
+type LinterCatalogEntry struct {
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	OriginalURL      string           `json:"originalUrl,omitempty"`
+	Presets          []string         `json:"presets,omitempty"`
+	EnabledByDefault bool             `json:"enabledByDefault"`
+	CanAutoFix       bool             `json:"canAutoFix"`
+	Since            string           `json:"since,omitempty"`
+	LoadMode         int              `json:"loadMode"`
+	Deprecation      *DeprecationInfo `json:"deprecation,omitempty"`
+	SettingsSchema   json.RawMessage  `json:"settingsSchema,omitempty"`
+}
+
+// DeprecationInfo mirrors linter.Config's deprecation block.
+type DeprecationInfo struct {
+	Since       string `json:"since,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// buildLintersCatalog turns the linter configs and the already-parsed
+// linters-settings YAML into the flat list written to lintersCatalogPath.
+func buildLintersCatalog(lcs []*linter.Config, settings []LinterSettings) ([]LinterCatalogEntry, error) {
+	settingsByName := make(map[string]*yaml.Node, len(settings))
+	for _, s := range settings {
+		settingsByName[s.Name] = s.Node
+	}
+
+	catalog := make([]LinterCatalogEntry, 0, len(lcs))
+	for _, lc := range lcs {
+		entry := LinterCatalogEntry{
+			Name:             lc.Name(),
+			Description:      lc.Linter.Desc(),
+			OriginalURL:      lc.OriginalURL,
+			Presets:          lc.InPresets,
+			EnabledByDefault: lc.EnabledByDefault,
+			CanAutoFix:       lc.CanAutoFix,
+			Since:            lc.Since,
+			LoadMode:         int(lc.LoadMode),
+		}
+
+		if lc.IsDeprecated() {
+			entry.Deprecation = &DeprecationInfo{
+				Since:       lc.Deprecation.Since,
+				Replacement: lc.Deprecation.Replacement,
+				Message:     lc.Deprecation.Message,
+			}
+		}
+
+		if node, ok := settingsByName[lc.Name()]; ok {
+			schema, err := json.Marshal(yamlNodeToSchema(node))
+			if err != nil {
+				return nil, fmt.Errorf("failed to build settings schema for %s: %w", lc.Name(), err)
+			}
+			entry.SettingsSchema = schema
+		}
+
+		catalog = append(catalog, entry)
+	}
+
+	return catalog, nil
+}
+
+// buildConfigSchema builds a JSON Schema for the whole .golangci.yml
+// configuration — not just linters-settings — from the same YAML AST walk
+// parseConfigExample already performs on the example config, so editors can
+// offer completion and validation against the current release's `run`,
+// `linters`, `linters-settings`, `issues` and `output` sections alike.
+func buildConfigSchema(root *yaml.Node) map[string]interface{} {
+	schema := yamlNodeToSchema(root)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "golangci-lint configuration"
+	return schema
+}
+
+// yamlNodeToSchema infers a minimal JSON Schema fragment from an example
+// value in .golangci.example.yml. It's deliberately simple: it only needs to
+// describe shapes that actually occur in an example config (scalars, lists,
+// and nested mappings), not arbitrary YAML.
+func yamlNodeToSchema(node *yaml.Node) map[string]interface{} {
+	if node == nil {
+		return map[string]interface{}{}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		properties := map[string]interface{}{}
+		for i := 0; i < len(node.Content); i += 2 {
+			properties[node.Content[i].Value] = yamlNodeToSchema(node.Content[i+1])
+		}
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+	case yaml.SequenceNode:
+		items := map[string]interface{}{}
+		if len(node.Content) > 0 {
+			items = yamlNodeToSchema(node.Content[0])
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": items,
+		}
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!bool":
+			return map[string]interface{}{"type": "boolean"}
+		case "!!int", "!!float":
+			return map[string]interface{}{"type": "number"}
+		default:
+			return map[string]interface{}{"type": "string"}
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// writeLintersAssetFiles writes the already-built JSON catalog and config
+// schema alongside the markdown docs. Like rewriteDocs, it's a working-tree
+// side effect and must only run when -only-state isn't set: updateStateFile
+// already covers ctx.LintersCatalog and ctx.ConfigSchema in its hash, so an
+// -only-state run doesn't need these files on disk to be reproducible.
+func writeLintersAssetFiles(ctx *TemplateContext) error {
+	catalogBytes, err := json.MarshalIndent(ctx.LintersCatalog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal linters catalog: %w", err)
+	}
+	if err := renameio.WriteFile(lintersCatalogPath, catalogBytes, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", lintersCatalogPath, err)
+	}
+
+	schemaBytes, err := json.MarshalIndent(ctx.ConfigSchema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config schema: %w", err)
+	}
+	if err := renameio.WriteFile(configSchemaPath, schemaBytes, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", configSchemaPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/golangci/golangci-lint/internal/renameio"
+	"github.com/golangci/golangci-lint/pkg/lint/linter"
+	"github.com/golangci/golangci-lint/pkg/lint/lintersdb"
+)
+
+// TemplateContext is the data model exposed to the docs templates under
+// docs/src/docs. It replaces the old flattened map[string]string so pages
+// can use text/template actions (conditionals, loops, partials) instead of
+// plain `{.Key}` substitution.
+type TemplateContext struct {
+	Linters           []*linter.Config
+	EnabledLinters    []*linter.Config
+	DisabledLinters   []*linter.Config
+	DeprecatedLinters []*linter.Config
+	Presets           []string
+	Settings          []LinterSettings
+
+	// LintersCatalog and ConfigSchema mirror docs/assets/linters.json and
+	// docs/assets/golangci-lint.schema.json: keeping them on the context
+	// means updateStateFile's hash already covers those generated files.
+	LintersCatalog []LinterCatalogEntry
+	ConfigSchema   map[string]interface{}
+
+	GolangciYamlExample              string
+	LintersCommandOutputEnabledOnly  string
+	LintersCommandOutputDisabledOnly string
+	RunHelpText                      string
+	ChangeLog                        string
+	LatestVersion                    string
+	ThanksList                       []ThanksAuthor
+}
+
+// LinterSettings holds the `linters-settings` YAML node for a single linter
+// key, alongside the already-rendered YAML snippet used by the settings page.
+type LinterSettings struct {
+	Name string
+	Node *yaml.Node
+	YAML string
+}
+
+// ThanksAuthor is a github author of a wrapped linter, deduplicated by handle.
+type ThanksAuthor struct {
+	Name string
+	URL  string
+}
+
+// partialsDir marks doc files that only declare named templates (via
+// `{{ define "name" }}`) for use through `include` and are never rendered
+// on their own, e.g. docs/src/docs/partials/_linters_table.md.tmpl.
+//
+// This is a dedicated directory rather than a filename convention like a
+// leading underscore: Hugo's `_index.md` section pages also start with an
+// underscore and are real, user-facing pages, not partials.
+const partialsDir = "partials"
+
+// legacyPlaceholderRe matches the old `{.Key}` / `{ .Key }` substitution
+// syntax, so a doc file that hasn't been migrated to `{{ }}` actions yet is
+// caught instead of silently rendering as-is. The single braces must not be
+// part of a `{{ .Key }}` action, which would otherwise match as well.
+var legacyPlaceholderRe = regexp.MustCompile(`(?:^|[^{])\{\s*\.[A-Za-z0-9_]+\s*\}(?:[^}]|$)`)
+
+func templateFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+		"split": strings.Split,
+		"date":  func(layout string, t time.Time) string { return t.Format(layout) },
+
+		"linterName":   getName,
+		"linterDesc":   getDesc,
+		"isDeprecated": func(lc *linter.Config) bool { return lc.IsDeprecated() },
+		"lintersTable": getLintersListMarkdown,
+	}
+}
+
+// templateName returns the name a doc file is registered and looked up
+// under: its slash-separated path relative to the docs root. A bare
+// filepath.Base collides whenever two sections share a filename (e.g. Hugo's
+// per-directory `_index.md`), silently rendering one directory's content
+// into a sibling's output file, so every doc file needs a name that's
+// unique across the whole tree.
+func templateName(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// isPartial reports whether path lives under root/partials/: files there
+// only declare named templates (via `{{ define "name" }}`) for use through
+// `include` and are never rendered on their own.
+func isPartial(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	first := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+	return first == partialsDir
+}
+
+// newDocTemplate parses every doc file into a single template set, each file
+// named by its path relative to root (see templateName), so that a template
+// declared with `{{ define "linters_table" }}` in one file (e.g. under
+// partials/) can be reached from any other with
+// `{{ include "linters_table" . }}`, and repeated tables (e.g. per-preset
+// linter tables) only need to be defined once.
+func newDocTemplate(root string, paths []string) (*template.Template, error) {
+	t := template.New(templateName(root, paths[0])).Funcs(templateFuncMap())
+	t = t.Funcs(template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			buf := &bytes.Buffer{}
+			if err := t.ExecuteTemplate(buf, name, data); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
+	})
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		name := templateName(root, path)
+
+		dest := t
+		if name != t.Name() {
+			dest = t.New(name)
+		}
+		if _, err := dest.Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	return t, nil
+}
+
+func rewriteDocs(ctx *TemplateContext) error {
+	root := filepath.Join("docs", "src", "docs")
+
+	var paths []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk dir: %w", err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no doc files found under %s", root)
+	}
+
+	var legacyFiles []string
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if legacyPlaceholderRe.Match(content) {
+			legacyFiles = append(legacyFiles, path)
+		}
+	}
+	if len(legacyFiles) > 0 {
+		return fmt.Errorf("%d doc file(s) still use the legacy {.Key}/{ .Key } placeholder syntax "+
+			"and must be migrated to {{ }} actions before they can be rendered: %s",
+			len(legacyFiles), strings.Join(legacyFiles, ", "))
+	}
+
+	tmpl, err := newDocTemplate(root, paths)
+	if err != nil {
+		return fmt.Errorf("failed to parse doc templates: %w", err)
+	}
+
+	var execErrs []string
+	rendered := 0
+	for _, path := range paths {
+		if isPartial(root, path) {
+			continue
+		}
+
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if !bytes.Contains(original, []byte("{{")) {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, templateName(root, path), ctx); err != nil {
+			execErrs = append(execErrs, fmt.Sprintf("%s: %s", path, err))
+			continue
+		}
+		rendered++
+
+		if buf.String() == string(original) {
+			continue
+		}
+
+		log.Printf("Expanded template in %s, saving it", path)
+		if err := renameio.WriteFile(path, buf.Bytes(), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to write changes to file %s: %w", path, err)
+		}
+	}
+
+	if len(execErrs) > 0 {
+		return fmt.Errorf("%d doc templates failed to render:\n%s", len(execErrs), strings.Join(execErrs, "\n"))
+	}
+
+	if rendered == 0 {
+		return fmt.Errorf("rendered zero doc templates under %s; expected at least one file using {{ }} actions", root)
+	}
+
+	return nil
+}
+
+func buildTemplateContext(latestVersionOverride string, onlyWriteState bool) (*TemplateContext, error) {
+	golangciYamlExample, err := os.ReadFile(".golangci.example.yml")
+	if err != nil {
+		return nil, fmt.Errorf("can't read .golangci.example.yml: %s", err)
+	}
+
+	configRoot, settings, err := parseConfigExample(golangciYamlExample)
+	if err != nil {
+		return nil, fmt.Errorf("can't read .golangci.example.yml: %s", err)
+	}
+
+	if err = exec.Command("make", "build").Run(); err != nil {
+		return nil, fmt.Errorf("can't run go install: %s", err)
+	}
+
+	lintersOut, err := exec.Command("./golangci-lint", "help", "linters").Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't run linters cmd: %s", err)
+	}
+
+	lintersOutParts := bytes.Split(lintersOut, []byte("\n\n"))
+
+	helpCmd := exec.Command("./golangci-lint", "run", "-h")
+	helpCmd.Env = append(helpCmd.Env, os.Environ()...)
+	helpCmd.Env = append(helpCmd.Env, "HELP_RUN=1") // make default concurrency stable: don't depend on machine CPU number
+	help, err := helpCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't run help cmd: %s", err)
+	}
+
+	helpLines := bytes.Split(help, []byte("\n"))
+	shortHelp := bytes.Join(helpLines[2:], []byte("\n"))
+	changeLog, err := os.ReadFile("CHANGELOG.md")
+	if err != nil {
+		return nil, err
+	}
+
+	latestVersion, err := resolveLatestVersion(latestVersionOverride, onlyWriteState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version: %s", err)
+	}
+
+	allLcs := lintersdb.NewManager(nil, nil).GetAllSupportedLinterConfigs()
+
+	var enabled, disabled, deprecated []*linter.Config
+	presetSet := map[string]bool{}
+	for _, lc := range allLcs {
+		if lc.EnabledByDefault {
+			enabled = append(enabled, lc)
+		} else {
+			disabled = append(disabled, lc)
+		}
+		if lc.IsDeprecated() {
+			deprecated = append(deprecated, lc)
+		}
+		for _, preset := range lc.InPresets {
+			presetSet[preset] = true
+		}
+	}
+	sortLintersByName(enabled)
+	sortLintersByName(disabled)
+	sortLintersByName(deprecated)
+
+	presets := make([]string, 0, len(presetSet))
+	for preset := range presetSet {
+		presets = append(presets, preset)
+	}
+	sort.Strings(presets)
+
+	catalog, err := buildLintersCatalog(allLcs, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build linters catalog: %w", err)
+	}
+	configSchema := buildConfigSchema(configRoot)
+
+	return &TemplateContext{
+		Linters:           allLcs,
+		EnabledLinters:    enabled,
+		DisabledLinters:   disabled,
+		DeprecatedLinters: deprecated,
+		Presets:           presets,
+		Settings:          settings,
+		LintersCatalog:    catalog,
+		ConfigSchema:      configSchema,
+
+		GolangciYamlExample:              strings.TrimSpace(string(golangciYamlExample)),
+		LintersCommandOutputEnabledOnly:  string(lintersOutParts[0]),
+		LintersCommandOutputDisabledOnly: string(lintersOutParts[1]),
+		RunHelpText:                      string(shortHelp),
+		ChangeLog:                        string(changeLog),
+		LatestVersion:                    latestVersion,
+		ThanksList:                       getThanksList(),
+	}, nil
+}
+
+func sortLintersByName(lcs []*linter.Config) {
+	sort.Slice(lcs, func(i, j int) bool {
+		return lcs[i].Name() < lcs[j].Name()
+	})
+}
+
+func getLintersListMarkdown(lcs []*linter.Config) string {
+	lines := []string{
+		"|Name|Description|Presets|AutoFix|Since|",
+		"|---|---|---|---|---|---|",
+	}
+
+	for _, lc := range lcs {
+		line := fmt.Sprintf("|%s|%s|%s|%v|%s|",
+			getName(lc),
+			getDesc(lc),
+			strings.Join(lc.InPresets, ", "),
+			check(lc.CanAutoFix, "Auto fix supported"),
+			lc.Since,
+		)
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func getName(lc *linter.Config) string {
+	name := lc.Name()
+
+	if lc.OriginalURL != "" {
+		name = fmt.Sprintf("[%s](%s)", lc.Name(), lc.OriginalURL)
+	}
+
+	if !lc.IsDeprecated() {
+		return name
+	}
+
+	title := "deprecated"
+	if lc.Deprecation.Replacement != "" {
+		title += fmt.Sprintf(" since %s", lc.Deprecation.Since)
+	}
+
+	return name + " " + span(title, "⚠")
+}
+
+func getDesc(lc *linter.Config) string {
+	desc := lc.Linter.Desc()
+	if lc.IsDeprecated() {
+		desc = lc.Deprecation.Message
+		if lc.Deprecation.Replacement != "" {
+			desc += fmt.Sprintf(" Replaced by %s.", lc.Deprecation.Replacement)
+		}
+	}
+
+	return strings.ReplaceAll(desc, "\n", "<br/>")
+}
+
+func check(b bool, title string) string {
+	if b {
+		return span(title, "✔")
+	}
+	return ""
+}
+
+func span(title, icon string) string {
+	return fmt.Sprintf(`<span title=%q>%s</span>`, title, icon)
+}
+
+func getThanksList() []ThanksAuthor {
+	var authors []ThanksAuthor
+	addedAuthors := map[string]bool{}
+	for _, lc := range lintersdb.NewManager(nil, nil).GetAllSupportedLinterConfigs() {
+		if lc.OriginalURL == "" {
+			continue
+		}
+
+		const githubPrefix = "https://github.com/"
+		if !strings.HasPrefix(lc.OriginalURL, githubPrefix) {
+			continue
+		}
+
+		githubSuffix := strings.TrimPrefix(lc.OriginalURL, githubPrefix)
+		githubAuthor := strings.Split(githubSuffix, "/")[0]
+		if addedAuthors[githubAuthor] {
+			continue
+		}
+		addedAuthors[githubAuthor] = true
+
+		authors = append(authors, ThanksAuthor{
+			Name: githubAuthor,
+			URL:  "https://github.com/" + githubAuthor,
+		})
+	}
+
+	return authors
+}
+
+// parseConfigExample parses .golangci.example.yml once and returns both the
+// full document root (used to build a JSON Schema for the whole config, not
+// just linters-settings) and the per-linter `linters-settings` breakdown,
+// keeping both the raw YAML node (for templates that want to walk it
+// directly) and the pre-rendered `### name` + fenced YAML snippet used by
+// the settings page today.
+func parseConfigExample(example []byte) (*yaml.Node, []LinterSettings, error) {
+	var data yaml.Node
+	if err := yaml.Unmarshal(example, &data); err != nil {
+		return nil, nil, err
+	}
+
+	root := data.Content[0]
+
+	var settings []LinterSettings
+	for j, node := range root.Content {
+		if node.Value != "linters-settings" {
+			continue
+		}
+
+		nodes := root.Content[j+1]
+
+		for i := 0; i < len(nodes.Content); i += 2 {
+			r := &yaml.Node{
+				Kind:  nodes.Kind,
+				Style: nodes.Style,
+				Tag:   nodes.Tag,
+				Value: node.Value,
+				Content: []*yaml.Node{
+					{
+						Kind:  root.Content[j].Kind,
+						Value: root.Content[j].Value,
+					},
+					{
+						Kind:    nodes.Kind,
+						Content: []*yaml.Node{nodes.Content[i], nodes.Content[i+1]},
+					},
+				},
+			}
+
+			builder := &strings.Builder{}
+			_, _ = fmt.Fprintf(builder, "### %s\n\n", nodes.Content[i].Value)
+			_, _ = fmt.Fprintln(builder, "```yaml")
+
+			const ident = 2
+			encoder := yaml.NewEncoder(builder)
+			encoder.SetIndent(ident)
+			if err := encoder.Encode(r); err != nil {
+				return nil, nil, err
+			}
+
+			_, _ = fmt.Fprintln(builder, "```")
+			_, _ = fmt.Fprintln(builder)
+
+			settings = append(settings, LinterSettings{
+				Name: nodes.Content[i].Value,
+				Node: nodes.Content[i+1],
+				YAML: builder.String(),
+			})
+		}
+	}
+
+	return root, settings, nil
+}
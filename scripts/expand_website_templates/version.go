@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/golangci/golangci-lint/internal/renameio"
+)
+
+// latestVersionEnv lets CI pin the version docs are generated for without
+// touching a flag, e.g. when the release tag isn't available as a local git
+// tag yet.
+const latestVersionEnv = "GOLANGCI_LINT_LATEST_VERSION"
+
+// latestVersionCachePath sits next to stateFilePath and remembers the last
+// version resolveLatestVersion looked up from local git tags or GitHub, so a
+// later `-only-state` run doesn't have to shell out to git or query GitHub
+// again. Versions taken from the override or latestVersionEnv are not cached.
+var latestVersionCachePath = filepath.Join(filepath.Dir(stateFilePath), "template_data.latest_version.json")
+
+var semverTagRe = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
+type latestRelease struct {
+	TagName string `json:"tag_name"`
+}
+
+type latestVersionCache struct {
+	Version string `json:"version"`
+}
+
+// resolveLatestVersion picks the version rendered into the docs as
+// `{{ .LatestVersion }}`. It prefers, in order, an explicit override, the
+// GOLANGCI_LINT_LATEST_VERSION env var, the highest semver tag already
+// present in the local git checkout, and only then falls back to the GitHub
+// API. This makes doc generation work offline and reproducibly: a checkout
+// with its tags fetched never needs network access to render docs.
+//
+// The cache is only ever consulted on an `-only-state` run: that mode exists
+// to cheaply refresh the state hash (e.g. in a pre-commit check) without
+// paying for a network round trip, and must not block a real docs render
+// from picking up a just-published release. Without an override or env var,
+// a full render always resolves fresh and refreshes the cache for the next
+// `-only-state` run to reuse; an override or env var value is returned as is
+// and leaves the cache untouched.
+func resolveLatestVersion(override string, onlyWriteState bool) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	if v := os.Getenv(latestVersionEnv); v != "" {
+		return v, nil
+	}
+
+	if onlyWriteState {
+		if v, ok := readLatestVersionCache(); ok {
+			return v, nil
+		}
+	}
+
+	version, err := resolveLatestVersionUncached()
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeLatestVersionCache(version); err != nil {
+		log.Printf("failed to cache resolved latest version: %s", err)
+	}
+
+	return version, nil
+}
+
+func resolveLatestVersionUncached() (string, error) {
+	if v, err := getLatestVersionFromLocalTags(); err == nil && v != "" {
+		return v, nil
+	}
+
+	return getLatestVersionFromGitHub()
+}
+
+func readLatestVersionCache() (string, bool) {
+	data, err := os.ReadFile(latestVersionCachePath)
+	if err != nil {
+		return "", false
+	}
+
+	var cache latestVersionCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Version == "" {
+		return "", false
+	}
+
+	return cache.Version, true
+}
+
+func writeLatestVersionCache(version string) error {
+	data, err := json.Marshal(latestVersionCache{Version: version})
+	if err != nil {
+		return fmt.Errorf("failed to marshal latest version cache: %w", err)
+	}
+
+	return renameio.WriteFile(latestVersionCachePath, data, os.ModePerm)
+}
+
+// getLatestVersionFromLocalTags shells out to git instead of depending on
+// go-git: the generator already assumes a working `git` binary (it's run
+// from within a checkout as part of `make`), and this avoids pulling in a
+// full git implementation for a one-line `git tag` call.
+func getLatestVersionFromLocalTags() (string, error) {
+	out, err := exec.Command("git", "tag", "--sort=-v:refname").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list local git tags: %w", err)
+	}
+
+	for _, tag := range strings.Split(string(out), "\n") {
+		tag = strings.TrimSpace(tag)
+		if semverTagRe.MatchString(tag) {
+			return tag, nil
+		}
+	}
+
+	return "", fmt.Errorf("no semver-shaped tags found in local checkout")
+}
+
+func getLatestVersionFromGitHub() (string, error) {
+	req, err := http.NewRequest( // nolint:noctx
+		http.MethodGet,
+		"https://api.github.com/repos/golangci/golangci-lint/releases/latest",
+		http.NoBody,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare a http request: %s", err)
+	}
+	req.Header.Add("Accept", "application/vnd.github.v3+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get http response for the latest tag: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read a body for the latest tag: %s", err)
+	}
+	release := latestRelease{}
+	err = json.Unmarshal(body, &release)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal the body for the latest tag: %s", err)
+	}
+	return release.TagName, nil
+}